@@ -5,19 +5,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/image/font/sfnt"
 )
 
 var workspaceRoot = filepath.Join(os.TempDir(), "onesub_workspace")
@@ -40,7 +48,19 @@ func main() {
 	r.POST("/api/upload", handleUpload)
 	r.GET("/api/media", handleMedia)
 	r.POST("/api/render", handleRender)
+	r.GET("/api/jobs/:id", handleJobStatus)
+	r.GET("/api/jobs/:id/events", handleJobEvents)
+	r.POST("/api/jobs/:id/cancel", handleJobCancel)
+	r.GET("/api/subtitles", handleSubtitles)
 	r.GET("/api/fonts", handleFonts)
+	r.GET("/api/workspaces", handleWorkspaces)
+	r.GET("/api/workspaces/:token", handleWorkspaceDetail)
+	r.DELETE("/api/workspaces/:token", handleWorkspaceDelete)
+	r.POST("/api/workspaces/:token/resume", handleWorkspaceResume)
+	r.POST("/api/watch", handleWatch)
+	r.DELETE("/api/watch/:token", handleWatchStop)
+
+	startWorkspaceJanitor()
 
 	log.Printf("OneSub backend listening on :%s", port)
 	if err := r.Run(":" + port); err != nil {
@@ -93,6 +113,7 @@ func handleUpload(c *gin.Context) {
 		Token:     token,
 		MediaName: file.Filename,
 		MediaPath: mediaPath,
+		CreatedAt: time.Now(),
 	}
 	if err := writeJSON(filepath.Join(workspace, "metadata.json"), meta); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -125,11 +146,37 @@ func handleMedia(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if _, err := os.Stat(path); err != nil {
+
+	file, info, err := openWorkspaceFile(path)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	c.File(path)
+	defer file.Close()
+
+	// http.ServeContent honors the Range header and emits 206/Last-Modified,
+	// which is required for <video> scrubbing to work on large files. No ETag
+	// header is set here, so ServeContent sends none.
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
+}
+
+// openWorkspaceFile opens path for range-based serving, returning its FileInfo
+// alongside the handle so callers can feed http.ServeContent without a second stat.
+func openWorkspaceFile(path string) (*os.File, os.FileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, nil, errors.New("not a file")
+	}
+	return file, info, nil
 }
 
 // --- Render ------------------------------------------------------------------
@@ -180,13 +227,429 @@ func handleRender(c *gin.Context) {
 	captionsPath := filepath.Join(workspace, "captions.json")
 	analysisPath := filepath.Join(workspace, "audio_analysis.json")
 
-	if err := runRender(meta.MediaPath, captionsPath, analysisPath, configPath, outputPath); err != nil {
+	job := newRenderJob()
+	go job.run(req.Token, outputName, meta.MediaPath, captionsPath, analysisPath, configPath, outputPath)
+
+	c.JSON(http.StatusAccepted, gin.H{"jobId": job.ID})
+}
+
+// --- Jobs --------------------------------------------------------------------
+
+type jobStatus string
+
+const (
+	jobQueued  jobStatus = "queued"
+	jobRunning jobStatus = "running"
+	jobDone    jobStatus = "done"
+	jobError   jobStatus = "error"
+)
+
+// jobEvent is pushed to status polls and SSE subscribers as progress is made.
+type jobEvent struct {
+	Stage     string  `json:"stage"`
+	Progress  float64 `json:"progress"`
+	Message   string  `json:"message"`
+	RenderURL string  `json:"renderUrl,omitempty"`
+}
+
+type renderJob struct {
+	ID        string    `json:"id"`
+	Status    jobStatus `json:"status"`
+	Stage     string    `json:"stage"`
+	Progress  float64   `json:"progress"`
+	Message   string    `json:"message"`
+	Error     string    `json:"error,omitempty"`
+	RenderURL string    `json:"renderUrl,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt,omitempty"`
+
+	mu          sync.Mutex
+	cmd         *exec.Cmd
+	subscribers []chan jobEvent
+	watch       bool // true for a job driven by the watch subsystem, which re-renders repeatedly
+}
+
+var (
+	jobsMu sync.Mutex
+	jobs   = map[string]*renderJob{}
+)
+
+func newRenderJob() *renderJob {
+	job := &renderJob{
+		ID:        fmt.Sprintf("job_%d", time.Now().UnixNano()),
+		Status:    jobQueued,
+		StartedAt: time.Now(),
+	}
+	jobsMu.Lock()
+	jobs[job.ID] = job
+	jobsMu.Unlock()
+	return job
+}
+
+func getJob(id string) (*renderJob, bool) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	job, ok := jobs[id]
+	return job, ok
+}
+
+// publish records the event on the job and fans it out to any SSE subscribers.
+// publish records the event and fans it out to subscribers while holding j.mu
+// for the whole send, not just the snapshot — closeSubscribers takes the same
+// lock to close channels, so a send can never race a close of the same channel.
+func (j *renderJob) publish(event jobEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Stage = event.Stage
+	j.Progress = event.Progress
+	j.Message = event.Message
+
+	for _, ch := range j.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (j *renderJob) subscribe() chan jobEvent {
+	ch := make(chan jobEvent, 16)
+	j.mu.Lock()
+	j.subscribers = append(j.subscribers, ch)
+	j.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes ch from the subscriber list and closes it. It is a no-op
+// if closeSubscribers already removed (and closed) ch first.
+func (j *renderJob) unsubscribe(ch chan jobEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for i, sub := range j.subscribers {
+		if sub == ch {
+			j.subscribers = append(j.subscribers[:i], j.subscribers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// closeSubscribers disconnects every SSE listener currently attached to the
+// job; used when a watch is stopped so its /api/jobs/:id/events streams end.
+func (j *renderJob) closeSubscribers() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, ch := range j.subscribers {
+		close(ch)
+	}
+	j.subscribers = nil
+}
+
+func (j *renderJob) finish(status jobStatus, renderURL string, err error) {
+	j.mu.Lock()
+	j.Status = status
+	j.EndedAt = time.Now()
+	if err != nil {
+		j.Error = err.Error()
+	}
+	j.RenderURL = renderURL
+	j.mu.Unlock()
+
+	stage := "done"
+	message := "Render complete"
+	progress := 1.0
+	if err != nil {
+		stage = "error"
+		message = err.Error()
+		progress = 0
+	}
+	j.publish(jobEvent{Stage: stage, Progress: progress, Message: message, RenderURL: renderURL})
+}
+
+func (j *renderJob) run(token, outputName, videoPath, captionsPath, analysisPath, configPath, outputPath string) {
+	j.mu.Lock()
+	j.Status = jobRunning
+	j.mu.Unlock()
+	j.publish(jobEvent{Stage: "render", Progress: 0, Message: "starting render"})
+
+	if err := runRenderJob(j, videoPath, captionsPath, analysisPath, configPath, outputPath); err != nil {
+		j.finish(jobError, "", err)
+		return
+	}
+
+	workspace := filepath.Join(workspaceRoot, token)
+	if err := markWorkspaceRendered(workspace, outputName); err != nil {
+		log.Printf("failed to record render on workspace metadata: %v", err)
+	}
+
+	renderURL := fmt.Sprintf("/api/media?token=%s&file=%s", url.QueryEscape(token), url.QueryEscape(outputName))
+	j.finish(jobDone, renderURL, nil)
+}
+
+func handleJobStatus(c *gin.Context) {
+	job, ok := getJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	c.JSON(http.StatusOK, job)
+}
+
+func handleJobEvents(c *gin.Context) {
+	job, ok := getJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	ch := job.subscribe()
+	defer job.unsubscribe(ch)
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return false
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			if job.watch {
+				// a watch job keeps rendering on every file change, so its
+				// stream stays open past done/error until the watch is stopped.
+				return true
+			}
+			return event.Stage != "done" && event.Stage != "error"
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func handleJobCancel(c *gin.Context) {
+	job, ok := getJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	job.mu.Lock()
+	cmd := job.cmd
+	job.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "job is not running"})
+		return
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "cancel requested"})
+}
+
+// --- Watch -------------------------------------------------------------------
+
+type watchRequest struct {
+	Token      string         `json:"token"`
+	Settings   renderSettings `json:"settings"`
+	DebounceMs int            `json:"debounceMs"`
+}
+
+// fileWatch debounces fsnotify events for one workspace and re-runs the render
+// pipeline on every settled change, reusing a single renderJob so its SSE
+// stream (GET /api/jobs/:id/events) keeps delivering renderUrl updates.
+type fileWatch struct {
+	token    string
+	settings renderSettings
+	debounce time.Duration
+	watcher  *fsnotify.Watcher
+	job      *renderJob
+	stopCh   chan struct{}
+}
+
+// watchedFileNames lists the basenames a workspace watch reacts to; events
+// for any other file in the directory are ignored.
+var watchedFileNames = []string{"captions.json", "render_config.json", "placements.json"}
+
+// isWatchedFile reports whether a directory-watch event's path is one of the
+// files a render watch should react to.
+func isWatchedFile(path string) bool {
+	name := filepath.Base(path)
+	for _, watched := range watchedFileNames {
+		if name == watched {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	watchesMu sync.Mutex
+	watches   = map[string]*fileWatch{}
+)
+
+func handleWatch(c *gin.Context) {
+	var req watchRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing token"})
+		return
+	}
+
+	workspace := filepath.Join(workspaceRoot, req.Token)
+	if _, err := readMetadata(workspace); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown workspace"})
+		return
+	}
+
+	stopWatch(req.Token)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := watcher.Add(workspace); err != nil {
+		watcher.Close()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	debounce := time.Duration(req.DebounceMs) * time.Millisecond
+	if debounce <= 0 {
+		debounce = 500 * time.Millisecond
+	}
+
+	job := newRenderJob()
+	job.watch = true
+
+	fw := &fileWatch{
+		token:    req.Token,
+		settings: req.Settings,
+		debounce: debounce,
+		watcher:  watcher,
+		job:      job,
+		stopCh:   make(chan struct{}),
+	}
+
+	watchesMu.Lock()
+	watches[req.Token] = fw
+	watchesMu.Unlock()
+
+	go fw.run()
+
+	c.JSON(http.StatusOK, gin.H{"jobId": job.ID})
+}
+
+func handleWatchStop(c *gin.Context) {
+	if !stopWatch(c.Param("token")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "watch not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "watch stopped"})
+}
+
+func stopWatch(token string) bool {
+	watchesMu.Lock()
+	fw, ok := watches[token]
+	if ok {
+		delete(watches, token)
+	}
+	watchesMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	close(fw.stopCh)
+	fw.watcher.Close()
+	fw.job.closeSubscribers()
+	return true
+}
+
+func (fw *fileWatch) run() {
+	var timer *time.Timer
+	var timerCh <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if !isWatchedFile(event.Name) {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(fw.debounce)
+			timerCh = timer.C
+		case <-timerCh:
+			timerCh = nil
+			fw.triggerRender()
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("watch %s: %v", fw.token, err)
+		case <-fw.stopCh:
+			return
+		}
+	}
+}
+
+func (fw *fileWatch) triggerRender() {
+	workspace := filepath.Join(workspaceRoot, fw.token)
+	meta, err := readMetadata(workspace)
+	if err != nil {
+		fw.job.publish(jobEvent{Stage: "error", Progress: 0, Message: err.Error()})
+		return
+	}
+
+	configPath, err := writeRenderConfig(workspace, fw.settings, nil, nil)
+	if err != nil {
+		fw.job.publish(jobEvent{Stage: "error", Progress: 0, Message: err.Error()})
+		return
+	}
+
+	captionsPath := filepath.Join(workspace, "captions.json")
+	analysisPath := filepath.Join(workspace, "audio_analysis.json")
+	outputName := "rendered.mp4"
+	outputPath := filepath.Join(workspace, outputName)
+
+	fw.job.mu.Lock()
+	fw.job.Status = jobRunning
+	fw.job.Error = ""
+	fw.job.mu.Unlock()
+	fw.job.publish(jobEvent{Stage: "render", Progress: 0, Message: "change detected, re-rendering"})
+
+	if err := runRenderJob(fw.job, meta.MediaPath, captionsPath, analysisPath, configPath, outputPath); err != nil {
+		fw.job.finish(jobError, "", err)
+		return
+	}
+
+	if err := markWorkspaceRendered(workspace, outputName); err != nil {
+		log.Printf("failed to record render on workspace metadata: %v", err)
+	}
 
-	renderURL := fmt.Sprintf("/api/media?token=%s&file=%s", url.QueryEscape(req.Token), url.QueryEscape(outputName))
-	c.JSON(http.StatusOK, renderResponse{RenderURL: renderURL, Message: "Render complete"})
+	renderURL := fmt.Sprintf("/api/media?token=%s&file=%s", url.QueryEscape(fw.token), url.QueryEscape(outputName))
+	fw.job.finish(jobDone, renderURL, nil)
 }
 
 // --- CLI Helpers -------------------------------------------------------------
@@ -206,7 +669,14 @@ func runPrepare(mediaPath, outputDir string) error {
 	return nil
 }
 
-func runRender(videoPath, captionsPath, analysisPath, configPath, outputPath string) error {
+// progressPattern matches the render CLI's own progress marker ("PROGRESS 0.42")
+// as well as ffmpeg's "out_time_ms=" lines so partial encodes still report progress.
+var progressPattern = regexp.MustCompile(`(?:^PROGRESS\s+([0-9.]+)|out_time_ms=(\d+))`)
+
+// runRenderJob runs the render CLI for job j, scanning its combined output
+// line-by-line for progress markers instead of piping it blindly to os.Stdout,
+// and keeps a reference to cmd on the job so handleJobCancel can signal it.
+func runRenderJob(j *renderJob, videoPath, captionsPath, analysisPath, configPath, outputPath string) error {
 	cmd, err := buildCommand("ONESUB_RENDER_CMD", "onesub.tasks.render")
 	if err != nil {
 		return err
@@ -219,14 +689,61 @@ func runRender(videoPath, captionsPath, analysisPath, configPath, outputPath str
 		"--output", outputPath,
 	)
 	applyWorkdir(cmd)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("onesub-render failed: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("onesub-render failed: %w", err)
+	}
+
+	j.mu.Lock()
+	j.cmd = cmd
+	j.mu.Unlock()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		log.Println(line)
+		if match := progressPattern.FindStringSubmatch(line); match != nil {
+			j.publish(jobEvent{Stage: "render", Progress: parseProgress(match), Message: line})
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
 		return fmt.Errorf("onesub-render failed: %w", err)
 	}
 	return nil
 }
 
+// parseProgress pulls a 0-1 progress value out of a progressPattern match,
+// normalizing ffmpeg's out_time_ms microsecond counter to a best-effort fraction.
+func parseProgress(match []string) float64 {
+	if match[1] != "" {
+		if v, err := strconv.ParseFloat(match[1], 64); err == nil {
+			return v
+		}
+	}
+	if match[2] != "" {
+		if ms, err := strconv.ParseFloat(match[2], 64); err == nil {
+			// out_time_ms has no known total duration here, so report a capped
+			// estimate that still moves the progress bar rather than a hard 0/1.
+			return minFloat(ms/1_000_000/60, 0.99)
+		}
+	}
+	return 0
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func buildCommand(envKey, module string) (*exec.Cmd, error) {
 	candidates := make([][]string, 0, 8)
 
@@ -348,9 +865,11 @@ type analysisFile struct {
 }
 
 type metadata struct {
-	Token     string `json:"token"`
-	MediaName string `json:"media_name"`
-	MediaPath string `json:"media_path"`
+	Token      string    `json:"token"`
+	MediaName  string    `json:"media_name"`
+	MediaPath  string    `json:"media_path"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastRender string    `json:"last_render,omitempty"`
 }
 
 type fontBand struct {
@@ -376,13 +895,25 @@ type renderSettings struct {
 	LineWordLimits  []int      `json:"lineWordLimits"`
 }
 
-type renderResponse struct {
-	RenderURL string `json:"renderUrl"`
-	Message   string `json:"message"`
+type fontsResponse struct {
+	Families []fontFamily `json:"families"`
 }
 
-type fontsResponse struct {
-	Fonts []string `json:"fonts"`
+// fontFace is one parsed face out of a font file's name table (a .ttc/.otc
+// collection yields several faces from a single path).
+type fontFace struct {
+	Family         string `json:"family"`
+	Subfamily      string `json:"subfamily"`
+	PostscriptName string `json:"postscriptName"`
+	Path           string `json:"path"`
+	Weight         string `json:"weight"`
+	Style          string `json:"style"`
+	Monospaced     bool   `json:"monospaced"`
+}
+
+type fontFamily struct {
+	Family string     `json:"family"`
+	Faces  []fontFace `json:"faces"`
 }
 
 // --- JSON helpers ------------------------------------------------------------
@@ -414,6 +945,17 @@ func readMetadata(workspace string) (metadata, error) {
 	return meta, json.Unmarshal(data, &meta)
 }
 
+// markWorkspaceRendered records the most recent render output on metadata.json
+// so workspace introspection can report hasRender without a directory scan.
+func markWorkspaceRendered(workspace, outputName string) error {
+	meta, err := readMetadata(workspace)
+	if err != nil {
+		return err
+	}
+	meta.LastRender = outputName
+	return writeJSON(filepath.Join(workspace, "metadata.json"), meta)
+}
+
 func writeJSON(path string, data any) error {
 	bytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -513,7 +1055,12 @@ func writeRenderConfig(workspace string, settings renderSettings, segments []seg
 		display["windows_path"] = windowsPath
 	}
 
-	defaultFont := strings.TrimSpace(settings.DefaultFont)
+	fontFamilies, err := listAvailableFonts()
+	if err != nil {
+		fontFamilies = nil
+	}
+
+	defaultFont := resolveFontPostscriptName(settings.DefaultFont, fontFamilies)
 	if defaultFont == "" {
 		defaultFont = "Arial"
 	}
@@ -526,7 +1073,7 @@ func writeRenderConfig(workspace string, settings renderSettings, segments []seg
 		fontBands = append(fontBands, map[string]any{
 			"min_size": band.MinSize,
 			"max_size": band.MaxSize,
-			"font":     band.Font,
+			"font":     resolveFontPostscriptName(band.Font, fontFamilies),
 		})
 	}
 
@@ -571,66 +1118,877 @@ func writeRenderConfig(workspace string, settings renderSettings, segments []seg
 	return configPath, nil
 }
 
-// --- Fonts -------------------------------------------------------------------
+// --- Subtitles ---------------------------------------------------------------
 
-func handleFonts(c *gin.Context) {
-	fonts, err := listAvailableFonts()
-	if err != nil {
-		log.Printf("failed to enumerate fonts: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enumerate fonts"})
+var subtitleContentTypes = map[string]string{
+	"srt": "application/x-subrip",
+	"vtt": "text/vtt",
+	"ass": "text/x-ssa",
+}
+
+func handleSubtitles(c *gin.Context) {
+	token := c.Query("token")
+	if err := validateToken(token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, fontsResponse{Fonts: fonts})
-}
 
-func listAvailableFonts() ([]string, error) {
-	paths := fontSearchPaths()
-	seen := map[string]struct{}{}
+	format := strings.ToLower(c.Query("format"))
+	contentType, ok := subtitleContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of srt, vtt, ass"})
+		return
+	}
 
-	for _, root := range paths {
-		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
-			if err != nil {
-				return nil
-			}
-			if entry.IsDir() {
-				return nil
-			}
-			ext := strings.ToLower(filepath.Ext(entry.Name()))
-			switch ext {
-			case ".ttf", ".otf", ".ttc", ".otc":
-			default:
-				return nil
-			}
+	workspace := filepath.Join(workspaceRoot, token)
+	tf, err := loadTranscript(filepath.Join(workspace, "captions.json"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	segments := transcriptToSegments(tf)
+	if analysis, err := loadAnalysis(filepath.Join(workspace, "audio_analysis.json")); err == nil {
+		segments, _ = mergeSegments(tf, analysis)
+	}
 
-			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
-			name = strings.TrimSpace(strings.ReplaceAll(name, "_", " "))
-			if name == "" {
-				name = entry.Name()
-			}
-			seen[name] = struct{}{}
-			return nil
-		})
-		if err != nil {
-			log.Printf("font scan warning for %s: %v", root, err)
+	var settings renderSettings
+	if raw := c.Query("settings"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid settings: " + err.Error()})
+			return
 		}
 	}
+	karaoke := c.Query("karaoke") == "1" || c.Query("karaoke") == "true"
 
-	fonts := make([]string, 0, len(seen))
-	for name := range seen {
-		fonts = append(fonts, name)
+	var body string
+	switch format {
+	case "srt":
+		body = generateSRT(segments, settings, karaoke)
+	case "vtt":
+		body = generateVTT(segments, settings, karaoke)
+	case "ass":
+		body = generateASS(segments, settings)
 	}
-	sort.Strings(fonts)
-	return fonts, nil
-}
 
-func fontSearchPaths() []string {
-	paths := map[string]struct{}{}
-	home, _ := os.UserHomeDir()
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
 
-	add := func(path string) {
-		if path == "" {
-			return
-		}
+// transcriptToSegments adapts the on-disk captions.json shape to the same
+// segmentResponse/wordResponse types the rest of the API already speaks.
+func transcriptToSegments(tf transcriptFile) []segmentResponse {
+	segments := make([]segmentResponse, 0, len(tf.Segments))
+	for _, seg := range tf.Segments {
+		words := make([]wordResponse, 0, len(seg.Words))
+		for _, word := range seg.Words {
+			words = append(words, wordResponse{ID: word.Index, Text: word.Text, Start: word.Start, End: word.End})
+		}
+		segments = append(segments, segmentResponse{
+			ID:    seg.Index,
+			Start: seg.Start,
+			End:   seg.End,
+			Text:  seg.Text,
+			Words: words,
+		})
+	}
+	return segments
+}
+
+func generateSRT(segments []segmentResponse, settings renderSettings, karaoke bool) string {
+	var b strings.Builder
+	for i, seg := range segments {
+		fmt.Fprintf(&b, "%d\n", i+1)
+		fmt.Fprintf(&b, "%s --> %s\n", formatSRTTimestamp(seg.Start), formatSRTTimestamp(seg.End))
+		b.WriteString(subtitleCueText(seg, settings, karaoke, false))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+func generateVTT(segments []segmentResponse, settings renderSettings, karaoke bool) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for i, seg := range segments {
+		fmt.Fprintf(&b, "%d\n", i+1)
+		fmt.Fprintf(&b, "%s --> %s\n", formatVTTTimestamp(seg.Start), formatVTTTimestamp(seg.End))
+		b.WriteString(subtitleCueText(seg, settings, karaoke, true))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// wrappedLine is one wrapped cue line together with the half-open range of
+// seg.Words it was built from, so callers never have to re-derive word counts
+// by re-splitting the already-joined Text (which breaks on empty or
+// space-containing word tokens).
+type wrappedLine struct {
+	Text      string
+	WordStart int
+	WordEnd   int
+}
+
+// subtitleCueText renders a cue's lines, honoring LineWordLimits for wrapping.
+// When karaoke is set, VTT cues prefix each word with a timestamp tag taken from
+// the word's Start and wrap it in <c>; SRT cues wrap each word in <c> with no
+// timing. Word End times are not used.
+func subtitleCueText(seg segmentResponse, settings renderSettings, karaoke, vtt bool) string {
+	lines := wrapSegmentWords(seg, settings.LineWordLimits)
+	if !karaoke || len(seg.Words) == 0 {
+		texts := make([]string, len(lines))
+		for i, line := range lines {
+			texts[i] = line.Text
+		}
+		return strings.Join(texts, "\n")
+	}
+
+	renderedLines := make([]string, 0, len(lines))
+	for _, line := range lines {
+		parts := make([]string, 0, line.WordEnd-line.WordStart)
+		for _, word := range seg.Words[line.WordStart:line.WordEnd] {
+			if vtt {
+				parts = append(parts, fmt.Sprintf("<%s><c>%s</c>", formatVTTTimestamp(word.Start), word.Text))
+			} else {
+				parts = append(parts, fmt.Sprintf("<c>%s</c>", word.Text))
+			}
+		}
+		renderedLines = append(renderedLines, strings.Join(parts, " "))
+	}
+	return strings.Join(renderedLines, "\n")
+}
+
+// wrapSegmentWords splits a segment's words into lines following lineWordLimits
+// (the last limit repeats for any remaining lines), falling back to the plain
+// segment text when there are no per-word timings or no limits configured.
+func wrapSegmentWords(seg segmentResponse, lineWordLimits []int) []wrappedLine {
+	if len(seg.Words) == 0 || len(lineWordLimits) == 0 {
+		return []wrappedLine{{Text: seg.Text, WordStart: 0, WordEnd: len(seg.Words)}}
+	}
+
+	lines := make([]wrappedLine, 0, len(lineWordLimits))
+	wordIdx, lineIdx := 0, 0
+	for wordIdx < len(seg.Words) {
+		limit := lineWordLimits[lineIdx]
+		if lineIdx < len(lineWordLimits)-1 {
+			lineIdx++
+		}
+		if limit <= 0 {
+			limit = len(seg.Words) - wordIdx
+		}
+		end := wordIdx + limit
+		if end > len(seg.Words) {
+			end = len(seg.Words)
+		}
+
+		words := make([]string, 0, end-wordIdx)
+		for _, word := range seg.Words[wordIdx:end] {
+			words = append(words, word.Text)
+		}
+		lines = append(lines, wrappedLine{Text: strings.Join(words, " "), WordStart: wordIdx, WordEnd: end})
+		wordIdx = end
+	}
+	return lines
+}
+
+func generateASS(segments []segmentResponse, settings renderSettings) string {
+	defaultFont := strings.TrimSpace(settings.DefaultFont)
+	if defaultFont == "" {
+		defaultFont = "Arial"
+	}
+	alignment := settings.Alignment
+	if alignment == 0 {
+		alignment = 2
+	}
+	fontSize := settings.SizeMax
+	if fontSize == 0 {
+		fontSize = 48
+	}
+
+	var b strings.Builder
+	b.WriteString("[Script Info]\n")
+	b.WriteString("ScriptType: v4.00+\n")
+	b.WriteString("WrapStyle: 0\n")
+	b.WriteString("ScaledBorderAndShadow: yes\n\n")
+
+	b.WriteString("[V4+ Styles]\n")
+	b.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	fmt.Fprintf(&b, "Style: Default,%s,%.0f,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,%.1f,%.1f,%d,10,10,10,1\n",
+		defaultFont, fontSize, settings.Outline, settings.Shadow, alignment)
+
+	for i, band := range settings.FontBands {
+		if band.Font == "" {
+			continue
+		}
+		bandSize := (band.MinSize + band.MaxSize) / 2
+		fmt.Fprintf(&b, "Style: Band%d,%s,%.0f,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,%.1f,%.1f,%d,10,10,10,1\n",
+			i, band.Font, bandSize, settings.Outline, settings.Shadow, alignment)
+	}
+	b.WriteString("\n")
+
+	maxRMS := maxWordRMS(segments)
+
+	b.WriteString("[Events]\n")
+	b.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, seg := range segments {
+		lines := wrapSegmentWords(seg, settings.LineWordLimits)
+		texts := make([]string, len(lines))
+		for i, line := range lines {
+			texts[i] = assLineText(seg, line, settings, maxRMS)
+		}
+		text := strings.Join(texts, "\\N")
+		fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", formatASSTimestamp(seg.Start), formatASSTimestamp(seg.End), text)
+	}
+
+	return b.String()
+}
+
+// maxWordRMS finds the loudest word across every segment so per-word sizing
+// can normalize against it instead of an arbitrary fixed scale.
+func maxWordRMS(segments []segmentResponse) float64 {
+	var max float64
+	for _, seg := range segments {
+		for _, word := range seg.Words {
+			if word.RMS > max {
+				max = word.RMS
+			}
+		}
+	}
+	return max
+}
+
+// assLineText renders one wrapped line's words, switching each word into the
+// FontBand style (via \r<name>) its RMS-mapped size falls into, mirroring the
+// per-word loudness-driven sizing the render pipeline itself applies.
+func assLineText(seg segmentResponse, line wrappedLine, settings renderSettings, maxRMS float64) string {
+	if len(seg.Words) == 0 || line.WordEnd <= line.WordStart {
+		return escapeASSText(line.Text)
+	}
+
+	var b strings.Builder
+	for wi := line.WordStart; wi < line.WordEnd; wi++ {
+		if wi > line.WordStart {
+			b.WriteString(" ")
+		}
+		word := seg.Words[wi]
+		size := wordFontSize(word.RMS, maxRMS, settings)
+		if styleName := fontBandStyleForSize(size, settings.FontBands); styleName != "" {
+			fmt.Fprintf(&b, "{\\r%s}%s{\\r}", styleName, escapeASSText(word.Text))
+		} else {
+			b.WriteString(escapeASSText(word.Text))
+		}
+	}
+	return b.String()
+}
+
+// wordFontSize maps a word's RMS, normalized against the loudest word in the
+// transcript, onto the [SizeMin, SizeMax] range the rest of the app uses.
+func wordFontSize(rms, maxRMS float64, settings renderSettings) float64 {
+	sizeMin, sizeMax := settings.SizeMin, settings.SizeMax
+	if sizeMin == 0 && sizeMax == 0 {
+		return 0
+	}
+	if sizeMax < sizeMin {
+		sizeMin, sizeMax = sizeMax, sizeMin
+	}
+	if maxRMS <= 0 {
+		return sizeMax
+	}
+
+	ratio := rms / maxRMS
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	return sizeMin + ratio*(sizeMax-sizeMin)
+}
+
+// fontBandStyleForSize finds the FontBand (by its [MinSize, MaxSize] range,
+// in declaration order) a font size falls into, returning its "BandN" style
+// name, or "" when size is unset or no band matches.
+func fontBandStyleForSize(size float64, bands []fontBand) string {
+	if size == 0 {
+		return ""
+	}
+	for i, band := range bands {
+		if band.Font == "" {
+			continue
+		}
+		lo, hi := band.MinSize, band.MaxSize
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if size >= lo && size <= hi {
+			return fmt.Sprintf("Band%d", i)
+		}
+	}
+	return ""
+}
+
+// escapeASSText neutralizes ASS override-block and newline syntax in
+// user-authored caption text so it can't break out of our own tags.
+func escapeASSText(s string) string {
+	return strings.NewReplacer("{", "(", "}", ")", "\n", "\\N").Replace(s)
+}
+
+func formatSRTTimestamp(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := time.Duration(seconds * float64(time.Second))
+	h := total / time.Hour
+	total -= h * time.Hour
+	m := total / time.Minute
+	total -= m * time.Minute
+	s := total / time.Second
+	total -= s * time.Second
+	ms := total / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+func formatVTTTimestamp(seconds float64) string {
+	return strings.Replace(formatSRTTimestamp(seconds), ",", ".", 1)
+}
+
+func formatASSTimestamp(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := time.Duration(seconds * float64(time.Second))
+	h := total / time.Hour
+	total -= h * time.Hour
+	m := total / time.Minute
+	total -= m * time.Minute
+	s := total / time.Second
+	total -= s * time.Second
+	cs := total / (10 * time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, cs)
+}
+
+// --- Workspaces ----------------------------------------------------------------
+
+type workspaceSummary struct {
+	Token     string    `json:"token"`
+	MediaName string    `json:"mediaName"`
+	CreatedAt time.Time `json:"createdAt"`
+	SizeBytes int64     `json:"sizeBytes"`
+	HasRender bool      `json:"hasRender"`
+}
+
+type workspaceFile struct {
+	Name  string    `json:"name"`
+	Size  int64     `json:"size"`
+	Mtime time.Time `json:"mtime"`
+	Mime  string    `json:"mime"`
+}
+
+func handleWorkspaces(c *gin.Context) {
+	summaries, err := listWorkspaces()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workspaces": summaries})
+}
+
+func handleWorkspaceDetail(c *gin.Context) {
+	token := c.Param("token")
+	if err := validateToken(token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workspace := filepath.Join(workspaceRoot, token)
+
+	meta, err := readMetadata(workspace)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workspace not found"})
+		return
+	}
+
+	entries, err := os.ReadDir(workspace)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	files := make([]workspaceFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, workspaceFile{
+			Name:  entry.Name(),
+			Size:  info.Size(),
+			Mtime: info.ModTime(),
+			Mime:  mime.TypeByExtension(filepath.Ext(entry.Name())),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metadata": meta, "files": files})
+}
+
+func handleWorkspaceDelete(c *gin.Context) {
+	token := c.Param("token")
+	if err := validateToken(token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workspace := filepath.Join(workspaceRoot, token)
+
+	if _, err := os.Stat(workspace); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workspace not found"})
+		return
+	}
+	stopWatch(token)
+	if err := os.RemoveAll(workspace); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "workspace removed"})
+}
+
+// handleWorkspaceResume re-derives the same payload handleUpload returns, so a
+// client that reloads the page can pick a prior session back up without
+// re-uploading and re-transcribing the source video.
+func handleWorkspaceResume(c *gin.Context) {
+	token := c.Param("token")
+	if err := validateToken(token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workspace := filepath.Join(workspaceRoot, token)
+
+	meta, err := readMetadata(workspace)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workspace not found"})
+		return
+	}
+
+	transcript, err := loadTranscript(filepath.Join(workspace, "captions.json"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	analysis, err := loadAnalysis(filepath.Join(workspace, "audio_analysis.json"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	segments, duration := mergeSegments(transcript, analysis)
+	mediaURL := fmt.Sprintf("/api/media?token=%s&file=%s", url.QueryEscape(token), url.QueryEscape(filepath.Base(meta.MediaPath)))
+
+	c.JSON(http.StatusOK, uploadResponse{
+		Token:     token,
+		MediaURL:  mediaURL,
+		MediaName: meta.MediaName,
+		Duration:  duration,
+		Segments:  segments,
+	})
+}
+
+func listWorkspaces() ([]workspaceSummary, error) {
+	entries, err := os.ReadDir(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]workspaceSummary, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		workspace := filepath.Join(workspaceRoot, entry.Name())
+		meta, err := readMetadata(workspace)
+		if err != nil {
+			continue
+		}
+		size, err := dirSize(workspace)
+		if err != nil {
+			log.Printf("failed to size workspace %s: %v", meta.Token, err)
+		}
+		summaries = append(summaries, workspaceSummary{
+			Token:     meta.Token,
+			MediaName: meta.MediaName,
+			CreatedAt: meta.CreatedAt,
+			SizeBytes: size,
+			HasRender: meta.LastRender != "",
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].CreatedAt.Before(summaries[j].CreatedAt) })
+	return summaries, nil
+}
+
+func dirSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// startWorkspaceJanitor launches the background eviction loop, enforcing a TTL
+// and/or a total-size cap across all workspaces. It is a no-op if neither
+// ONESUB_WORKSPACE_TTL nor ONESUB_WORKSPACE_MAX_BYTES is configured.
+func startWorkspaceJanitor() {
+	ttl := envDuration("ONESUB_WORKSPACE_TTL", 0)
+	maxBytes := envInt64("ONESUB_WORKSPACE_MAX_BYTES", 0)
+	if ttl <= 0 && maxBytes <= 0 {
+		return
+	}
+
+	const sweepInterval = 5 * time.Minute
+	go func() {
+		for {
+			janitorSweep(ttl, maxBytes)
+			time.Sleep(sweepInterval)
+		}
+	}()
+}
+
+func janitorSweep(ttl time.Duration, maxBytes int64) {
+	summaries, err := listWorkspaces()
+	if err != nil {
+		log.Printf("workspace janitor: %v", err)
+		return
+	}
+
+	now := time.Now()
+	kept := make([]workspaceSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if ttl > 0 && now.Sub(summary.CreatedAt) > ttl {
+			evictWorkspace(summary.Token)
+			continue
+		}
+		kept = append(kept, summary)
+	}
+
+	if maxBytes <= 0 {
+		return
+	}
+
+	var total int64
+	for _, summary := range kept {
+		total += summary.SizeBytes
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].CreatedAt.Before(kept[j].CreatedAt) })
+	for total > maxBytes && len(kept) > 0 {
+		oldest := kept[0]
+		kept = kept[1:]
+		evictWorkspace(oldest.Token)
+		total -= oldest.SizeBytes
+	}
+}
+
+func evictWorkspace(token string) {
+	workspace := filepath.Join(workspaceRoot, token)
+	stopWatch(token)
+	if err := os.RemoveAll(workspace); err != nil {
+		log.Printf("workspace janitor: failed to evict %s: %v", token, err)
+		return
+	}
+	log.Printf("workspace janitor: evicted %s", token)
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("invalid %s=%q: %v", key, raw, err)
+		return fallback
+	}
+	return d
+}
+
+func envInt64(key string, fallback int64) int64 {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		log.Printf("invalid %s=%q: %v", key, raw, err)
+		return fallback
+	}
+	return v
+}
+
+// --- Fonts -------------------------------------------------------------------
+
+func handleFonts(c *gin.Context) {
+	families, err := listAvailableFonts()
+	if err != nil {
+		log.Printf("failed to enumerate fonts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enumerate fonts"})
+		return
+	}
+	c.JSON(http.StatusOK, fontsResponse{Families: families})
+}
+
+// fontCachePath is where parsed name-table results are memoized, keyed by
+// (path, mtime, size) so unchanged files never need re-parsing with sfnt.
+var fontCachePath = filepath.Join(workspaceRoot, "fonts_cache.json")
+
+func listAvailableFonts() ([]fontFamily, error) {
+	cache, err := loadFontCache()
+	if err != nil {
+		cache = map[string][]fontFace{}
+	}
+	dirty := false
+
+	order := []string{}
+	byFamily := map[string][]fontFace{}
+
+	for _, root := range fontSearchPaths() {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			switch ext {
+			case ".ttf", ".otf", ".ttc", ".otc":
+			default:
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+
+			key := fontCacheKey(path, info)
+			faces, ok := cache[key]
+			if !ok {
+				faces, err = parseFontFile(path)
+				if err != nil {
+					log.Printf("font parse warning for %s: %v", path, err)
+					return nil
+				}
+				cache[key] = faces
+				dirty = true
+			}
+
+			for _, face := range faces {
+				if _, seen := byFamily[face.Family]; !seen {
+					order = append(order, face.Family)
+				}
+				byFamily[face.Family] = append(byFamily[face.Family], face)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("font scan warning for %s: %v", root, err)
+		}
+	}
+
+	if dirty {
+		if err := saveFontCache(cache); err != nil {
+			log.Printf("failed to persist font cache: %v", err)
+		}
+	}
+
+	sort.Strings(order)
+	families := make([]fontFamily, 0, len(order))
+	for _, name := range order {
+		families = append(families, fontFamily{Family: name, Faces: byFamily[name]})
+	}
+	return families, nil
+}
+
+// fontCacheKey identifies a font file's content well enough to invalidate the
+// cache on edit without hashing the (potentially large) file contents.
+func fontCacheKey(path string, info fs.FileInfo) string {
+	return fmt.Sprintf("%s|%d|%d", path, info.ModTime().UnixNano(), info.Size())
+}
+
+func loadFontCache() (map[string][]fontFace, error) {
+	data, err := os.ReadFile(fontCachePath)
+	if err != nil {
+		return nil, err
+	}
+	cache := map[string][]fontFace{}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+func saveFontCache(cache map[string][]fontFace) error {
+	return writeJSON(fontCachePath, cache)
+}
+
+// parseFontFile opens a single font file with sfnt and returns one fontFace
+// per face (a .ttc/.otc collection holds several; .ttf/.otf hold exactly one).
+func parseFontFile(path string) ([]fontFace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var faces []*sfnt.Font
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ttc", ".otc":
+		collection, err := sfnt.ParseCollection(data)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < collection.NumFonts(); i++ {
+			face, err := collection.Font(i)
+			if err != nil {
+				continue
+			}
+			faces = append(faces, face)
+		}
+	default:
+		face, err := sfnt.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		faces = append(faces, face)
+	}
+
+	result := make([]fontFace, 0, len(faces))
+	var buf sfnt.Buffer
+	for _, face := range faces {
+		result = append(result, fontFaceFromSfnt(face, &buf, path))
+	}
+	return result, nil
+}
+
+func fontFaceFromSfnt(f *sfnt.Font, buf *sfnt.Buffer, path string) fontFace {
+	family, _ := f.Name(buf, sfnt.NameIDFamily)
+	if typographic, err := f.Name(buf, sfnt.NameIDTypographicFamily); err == nil && typographic != "" {
+		family = typographic
+	}
+	if family == "" {
+		family = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	subfamily, _ := f.Name(buf, sfnt.NameIDSubfamily)
+	postscriptName, _ := f.Name(buf, sfnt.NameIDPostScript)
+
+	weight, style := weightAndStyleFromSubfamily(subfamily)
+	monospaced := strings.Contains(strings.ToLower(family), "mono") || strings.Contains(strings.ToLower(subfamily), "mono")
+
+	return fontFace{
+		Family:         family,
+		Subfamily:      subfamily,
+		PostscriptName: postscriptName,
+		Path:           path,
+		Weight:         weight,
+		Style:          style,
+		Monospaced:     monospaced,
+	}
+}
+
+// weightAndStyleFromSubfamily maps a name-table subfamily string (e.g.
+// "Bold Italic") to the coarse weight/style buckets the frontend and the
+// Python renderer agree on.
+func weightAndStyleFromSubfamily(subfamily string) (weight, style string) {
+	lower := strings.ToLower(subfamily)
+
+	style = "normal"
+	if strings.Contains(lower, "italic") || strings.Contains(lower, "oblique") {
+		style = "italic"
+	}
+
+	weight = "regular"
+	switch {
+	case strings.Contains(lower, "thin"):
+		weight = "thin"
+	case strings.Contains(lower, "extralight"), strings.Contains(lower, "light"):
+		weight = "light"
+	case strings.Contains(lower, "medium"):
+		weight = "medium"
+	case strings.Contains(lower, "semibold"):
+		weight = "semibold"
+	case strings.Contains(lower, "extrabold"), strings.Contains(lower, "black"), strings.Contains(lower, "heavy"):
+		weight = "black"
+	case strings.Contains(lower, "bold"):
+		weight = "bold"
+	}
+
+	return weight, style
+}
+
+// resolveFontPostscriptName looks up name (a family, subfamily, or already a
+// PostScript name) against families, an already-fetched font catalog, and
+// returns its PostScript name, which is what libass/PIL reliably resolve to
+// the correct face. Unknown names pass through unchanged so callers can still
+// reference system fonts that were not found on disk.
+//
+// When name matches a family as a whole rather than a specific face, the
+// Regular/normal face of that family is preferred over whatever face happens
+// to sort first, so a bare family name like "Arial" doesn't resolve to
+// "Arial-Bold" depending on filesystem ordering.
+func resolveFontPostscriptName(name string, families []fontFamily) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return name
+	}
+
+	for _, family := range families {
+		if family.Family != name {
+			continue
+		}
+		if face, ok := regularFace(family.Faces); ok {
+			return face.PostscriptName
+		}
+	}
+
+	for _, family := range families {
+		for _, face := range family.Faces {
+			if face.PostscriptName == "" {
+				continue
+			}
+			if face.PostscriptName == name || face.Family == name || face.Subfamily == name {
+				return face.PostscriptName
+			}
+		}
+	}
+	return name
+}
+
+// regularFace returns the Regular-weight, normal-style face among faces, if
+// any has a usable PostScript name.
+func regularFace(faces []fontFace) (fontFace, bool) {
+	for _, face := range faces {
+		if face.PostscriptName != "" && face.Weight == "regular" && face.Style == "normal" {
+			return face, true
+		}
+	}
+	return fontFace{}, false
+}
+
+func fontSearchPaths() []string {
+	paths := map[string]struct{}{}
+	home, _ := os.UserHomeDir()
+
+	add := func(path string) {
+		if path == "" {
+			return
+		}
 		if _, ok := paths[path]; ok {
 			return
 		}
@@ -679,7 +2037,22 @@ func fontSearchPaths() []string {
 
 // --- Misc helpers ------------------------------------------------------------
 
+// tokenPattern matches the numeric tokens handleUpload mints (UnixNano
+// timestamps), which also rules out "..", path separators, and anything else
+// that could escape workspaceRoot when joined into a filesystem path.
+var tokenPattern = regexp.MustCompile(`^[0-9]+$`)
+
+func validateToken(token string) error {
+	if !tokenPattern.MatchString(token) {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
 func resolveWorkspaceFile(token, fileName string) (string, error) {
+	if err := validateToken(token); err != nil {
+		return "", err
+	}
 	workspace := filepath.Join(workspaceRoot, token)
 	fullPath := filepath.Join(workspace, fileName)
 	if !strings.HasPrefix(fullPath, workspace) {
@@ -720,7 +2093,7 @@ func normalizeHexColor(value string, fallback string) string {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept")
 
 		if c.Request.Method == http.MethodOptions {